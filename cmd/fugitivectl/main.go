@@ -0,0 +1,130 @@
+// Command fugitivectl is an operator CLI for administering a running
+// Fugitive3dServerRepository instance.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/auth"
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/logging"
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/srvrepo"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case "issue-token":
+		issueToken(os.Args[2:])
+	case "migrate":
+		migrate(os.Args[2:])
+	default:
+		usage()
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: fugitivectl issue-token -secret-file <path> -ip <addr> -ttl <duration> [-server-id <id>] [-scope <scope>,...]")
+	fmt.Fprintln(os.Stderr, "       fugitivectl migrate -from <store spec> -to <store spec> [-ttl <duration>]")
+}
+
+func issueToken(args []string) {
+	fs := flag.NewFlagSet("issue-token", flag.ExitOnError)
+
+	var secretFile string
+	var serverID string
+	var ip string
+	var ttl time.Duration
+	var scopes string
+
+	fs.StringVar(&secretFile, "secret-file", "", "Path to the shared HMAC secret used to sign tokens (required)")
+	fs.StringVar(&serverID, "server-id", "", "Server ID the token is scoped to")
+	fs.StringVar(&ip, "ip", "", "IP address the token is scoped to (required)")
+	fs.DurationVar(&ttl, "ttl", time.Hour, "How long the token should remain valid")
+	fs.StringVar(&scopes, "scope", "register", "Comma-separated list of scopes to grant")
+	fs.Parse(args)
+
+	if secretFile == "" || ip == "" {
+		usage()
+		os.Exit(1)
+	}
+
+	raw, err := os.ReadFile(secretFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("reading secret file: %w", err))
+		os.Exit(1)
+	}
+	secret := []byte(strings.TrimSpace(string(raw)))
+
+	signer := auth.NewSigner(secret)
+
+	token, err := signer.Sign(serverID, ip, strings.Split(scopes, ","), ttl)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("signing token: %w", err))
+		os.Exit(1)
+	}
+
+	fmt.Println(token)
+}
+
+// migrate copies every server from one storage backend to another, e.g. to
+// move registrations from "memory" onto "bolt:///var/lib/fugitive/servers.db"
+// without losing them across the cutover.
+func migrate(args []string) {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+
+	var from string
+	var to string
+	var ttl time.Duration
+
+	fs.StringVar(&from, "from", "", "Store spec to copy servers from (required)")
+	fs.StringVar(&to, "to", "", "Store spec to copy servers to (required)")
+	fs.DurationVar(&ttl, "ttl", 30*time.Second, "Stale threshold, used by TTL-based backends such as redis://")
+	fs.Parse(args)
+
+	if from == "" || to == "" {
+		usage()
+		os.Exit(1)
+	}
+
+	logger, err := logging.NewProduction()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("building logger: %w", err))
+		os.Exit(1)
+	}
+
+	src, err := srvrepo.OpenStore(from, ttl, logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("opening -from store: %w", err))
+		os.Exit(1)
+	}
+	if closer, ok := src.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	dst, err := srvrepo.OpenStore(to, ttl, logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("opening -to store: %w", err))
+		os.Exit(1)
+	}
+	if closer, ok := dst.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	count, err := srvrepo.Migrate(dst, src)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("migrating servers: %w", err))
+		os.Exit(1)
+	}
+
+	fmt.Printf("migrated %d server(s) from %s to %s\n", count, from, to)
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// claimsContextKey is the gin context key the verified Claims are stored
+// under by RequireToken.
+const claimsContextKey = "auth_claims"
+
+// RequireToken returns a gin middleware that validates an `Authorization:
+// Bearer <token>` header using the given Signer and rejects the request
+// unless the token's claims grant all of the given scopes.
+func RequireToken(signer *Signer, scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+
+		const prefix = "Bearer "
+		if !strings.HasPrefix(header, prefix) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"result": "missing bearer token"})
+			return
+		}
+
+		claims, err := signer.Verify(strings.TrimPrefix(header, prefix))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"result": "invalid token"})
+			return
+		}
+
+		for _, scope := range scopes {
+			if !claims.HasScope(scope) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"result": "token missing required scope: " + scope})
+				return
+			}
+		}
+
+		c.Set(claimsContextKey, claims)
+		c.Next()
+	}
+}
+
+// ClaimsFromContext returns the Claims stored on the gin context by
+// RequireToken, if any.
+func ClaimsFromContext(c *gin.Context) (*Claims, bool) {
+	value, ok := c.Get(claimsContextKey)
+	if !ok {
+		return nil, false
+	}
+
+	claims, ok := value.(*Claims)
+
+	return claims, ok
+}
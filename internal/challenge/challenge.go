@@ -0,0 +1,168 @@
+// Package challenge implements a UDP challenge-response handshake that
+// proves a registering server actually controls the address it claims,
+// rather than trusting that its declared IP matches the TCP connection it
+// registered over. It works the way NAT hole-punching tools verify
+// reachability: the repository hands out a random nonce, and the
+// registering server must echo it back over UDP from its own address
+// before registration is allowed to proceed.
+package challenge
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds how long an issued nonce remains valid while
+// waiting for it to be echoed back.
+const DefaultTimeout = 10 * time.Second
+
+// nonceSize is the number of random bytes per challenge, hex-encoded in the
+// wire nonce string.
+const nonceSize = 16
+
+// pending tracks a single outstanding challenge.
+type pending struct {
+	addrPort netip.AddrPort
+	verified chan struct{}
+	once     sync.Once
+}
+
+// Listener issues nonces and verifies them by listening for matching UDP
+// packets echoed back by the registering server.
+type Listener struct {
+	conn    *net.UDPConn
+	timeout time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*pending
+}
+
+// Listen opens a UDP listener on the first available port within
+// [portMin, portMax] and returns a pointer to a new Listener. timeout bounds
+// how long an issued nonce remains valid before it must be retried.
+func Listen(portMin, portMax int, timeout time.Duration) (*Listener, error) {
+	var conn *net.UDPConn
+	var lastErr error
+
+	for port := portMin; port <= portMax; port++ {
+		c, err := net.ListenUDP("udp", &net.UDPAddr{Port: port})
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		conn = c
+		break
+	}
+
+	if conn == nil {
+		return nil, fmt.Errorf("binding challenge listener in port range %d-%d: %w", portMin, portMax, lastErr)
+	}
+
+	return &Listener{
+		conn:    conn,
+		timeout: timeout,
+		pending: make(map[string]*pending),
+	}, nil
+}
+
+// Addr returns the UDP address the listener is bound to, so callers can
+// advertise it to registering servers as the echo target.
+func (l *Listener) Addr() *net.UDPAddr {
+	return l.conn.LocalAddr().(*net.UDPAddr)
+}
+
+// Close closes the underlying UDP socket, causing any in-progress Run to
+// return.
+func (l *Listener) Close() error {
+	return l.conn.Close()
+}
+
+// Issue generates a new nonce for addrPort and records it as pending. The
+// nonce expires on its own after the Listener's timeout if it's never echoed
+// back. addrPort must include the server's declared game port, not just its
+// IP, so that Run can confirm the echo actually came from that port rather
+// than from any UDP socket at the same address.
+func (l *Listener) Issue(addrPort netip.AddrPort) (string, error) {
+	raw := make([]byte, nonceSize)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generating nonce: %w", err)
+	}
+	nonce := hex.EncodeToString(raw)
+
+	l.mu.Lock()
+	l.pending[nonce] = &pending{addrPort: addrPort, verified: make(chan struct{})}
+	l.mu.Unlock()
+
+	time.AfterFunc(l.timeout, func() {
+		l.mu.Lock()
+		delete(l.pending, nonce)
+		l.mu.Unlock()
+	})
+
+	return nonce, nil
+}
+
+// Verified reports whether the nonce issued for addrPort has since been
+// echoed back over UDP from that same address and port.
+func (l *Listener) Verified(nonce string, addrPort netip.AddrPort) bool {
+	l.mu.Lock()
+	p, ok := l.pending[nonce]
+	l.mu.Unlock()
+
+	if !ok || p.addrPort != addrPort {
+		return false
+	}
+
+	select {
+	case <-p.verified:
+		return true
+	default:
+		return false
+	}
+}
+
+// Run reads incoming UDP packets and marks the matching pending challenge
+// verified when its nonce is echoed back from the address it was issued to.
+// Run blocks the calling goroutine until ctx is cancelled, so callers
+// typically invoke it with `go listener.Run(ctx)`.
+func (l *Listener) Run(ctx context.Context) {
+	go func() {
+		<-ctx.Done()
+		l.conn.Close()
+	}()
+
+	buf := make([]byte, hex.EncodedLen(nonceSize))
+
+	for {
+		n, raddr, err := l.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+
+		ip, ok := netip.AddrFromSlice(raddr.IP)
+		if !ok {
+			continue
+		}
+		ip = ip.Unmap().WithZone("")
+		addrPort := netip.AddrPortFrom(ip, uint16(raddr.Port))
+
+		nonce := string(buf[:n])
+
+		l.mu.Lock()
+		p, exists := l.pending[nonce]
+		l.mu.Unlock()
+
+		if !exists || p.addrPort != addrPort {
+			continue
+		}
+
+		p.once.Do(func() { close(p.verified) })
+	}
+}
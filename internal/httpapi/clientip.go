@@ -0,0 +1,89 @@
+package httpapi
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ParseTrustedProxies parses a comma-separated list of CIDR prefixes (e.g.
+// "10.0.0.0/8,172.16.0.0/12") naming the proxies allowed to set
+// X-Forwarded-For/X-Real-IP on an incoming request. An empty spec returns a
+// nil slice, meaning no proxy is trusted and ClientIP always falls back to
+// the direct TCP peer address.
+func ParseTrustedProxies(spec string) ([]netip.Prefix, error) {
+	if spec == "" {
+		return nil, nil
+	}
+
+	var prefixes []netip.Prefix
+	for _, raw := range strings.Split(spec, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(raw)
+		if err != nil {
+			return nil, fmt.Errorf("parsing trusted proxy CIDR %q: %w", raw, err)
+		}
+
+		prefixes = append(prefixes, prefix)
+	}
+
+	return prefixes, nil
+}
+
+// isTrustedProxy reports whether addr falls within any of the given trusted
+// proxy prefixes.
+func isTrustedProxy(addr netip.Addr, trusted []netip.Prefix) bool {
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ClientIP returns the real client address for r. If the direct TCP peer
+// (r.RemoteAddr) is listed in trusted, the client address is instead read
+// from the X-Forwarded-For (its first, leftmost entry) or X-Real-IP header,
+// so that running behind a trusted reverse proxy (Cloudflare, an ingress,
+// etc.) doesn't make every registration appear to come from the proxy. The
+// returned address is canonicalized the same way ParseServerAddress
+// canonicalizes one, so it compares equal to a Server's IP regardless of
+// address family quirks.
+func ClientIP(r *http.Request, trusted []netip.Prefix) (netip.Addr, error) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("splitting remote address: %w", err)
+	}
+
+	peer, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("parsing remote address: %w", err)
+	}
+	peer = peer.Unmap().WithZone("")
+
+	if !isTrustedProxy(peer, trusted) {
+		return peer, nil
+	}
+
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		first := strings.TrimSpace(strings.Split(xff, ",")[0])
+		if addr, err := netip.ParseAddr(first); err == nil {
+			return addr.Unmap().WithZone(""), nil
+		}
+	}
+
+	if xri := r.Header.Get("X-Real-IP"); xri != "" {
+		if addr, err := netip.ParseAddr(strings.TrimSpace(xri)); err == nil {
+			return addr.Unmap().WithZone(""), nil
+		}
+	}
+
+	return peer, nil
+}
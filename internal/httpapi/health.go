@@ -0,0 +1,46 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// pinger is satisfied by srvrepo.ServerRepository, checked here rather than
+// imported directly so this package doesn't need to know about srvrepo's
+// concrete Store backends.
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// HandleLiveness is a gin HTTP handler for a liveness probe: if the process
+// can respond at all, it's alive.
+func HandleLiveness(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"result": "ok"})
+}
+
+// HandleReadiness returns a gin HTTP handler for a readiness probe: it
+// reports 503 while repo's backing Store can't be reached, so a load
+// balancer stops sending traffic here until the backend recovers.
+func HandleReadiness(repo pinger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if err := repo.Ping(ctx.Request.Context()); err != nil {
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"result": "backend unreachable"})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"result": "ok"})
+	}
+}
+
+// HandleMetrics is a gin HTTP handler exposing every registered Prometheus
+// collector in the text exposition format.
+func HandleMetrics() gin.HandlerFunc {
+	handler := promhttp.Handler()
+
+	return func(ctx *gin.Context) {
+		handler.ServeHTTP(ctx.Writer, ctx.Request)
+	}
+}
@@ -0,0 +1,96 @@
+package httpapi
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/logging"
+)
+
+// requestIDContextKey is the gin context key the per-request ID is stashed
+// under so that later middlewares (and RequestIDFromGinContext) can pick it up.
+const requestIDContextKey = "request_id"
+
+// spanContextKey is the gin context key the request's trace.Span is stashed
+// under.
+const spanContextKey = "span"
+
+// tracerName identifies this package's spans in exported traces.
+const tracerName = "github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/httpapi"
+
+// RequestID returns a gin middleware that assigns a UUID to every request,
+// making it available on the gin context (see RequestIDFromGinContext) and
+// echoing it back via the X-Request-ID response header so a caller can
+// correlate their request with our logs.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := uuid.NewString()
+
+		c.Set(requestIDContextKey, requestID)
+		c.Header("X-Request-ID", requestID)
+
+		// Thread the request ID onto the Go context too, so that code several
+		// layers away from this handler (e.g. an outbound health probe) can
+		// still log with it attached.
+		c.Request = c.Request.WithContext(logging.ContextWithRequestID(c.Request.Context(), requestID))
+
+		c.Next()
+	}
+}
+
+// RequestIDFromGinContext returns the request ID assigned by RequestID, or
+// the empty string if the middleware hasn't run.
+func RequestIDFromGinContext(c *gin.Context) string {
+	value, _ := c.Get(requestIDContextKey)
+	requestID, _ := value.(string)
+
+	return requestID
+}
+
+// RequestLogger returns a gin middleware that logs one line per completed
+// request through logger, replacing gin-glog so request logs go through the
+// same sink (and carry the same request_id) as every other log line.
+// It must run after RequestID so the request ID is already set on the gin
+// context.
+func RequestLogger(logger logging.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		logger.Info("handled request",
+			logging.String("request_id", RequestIDFromGinContext(c)),
+			logging.String("method", c.Request.Method),
+			logging.String("path", c.FullPath()),
+			logging.String("client_ip", c.ClientIP()),
+			logging.String("status", strconv.Itoa(c.Writer.Status())),
+			logging.String("latency", time.Since(start).String()),
+		)
+	}
+}
+
+// Tracing returns a gin middleware that starts an OpenTelemetry span for
+// every request, propagating an incoming "traceparent" header if present and
+// making the span available on the gin context and the request's Go context
+// so handlers and repository calls can add their own sub-spans.
+func Tracing() gin.HandlerFunc {
+	propagator := propagation.TraceContext{}
+	tracer := otel.Tracer(tracerName)
+
+	return func(c *gin.Context) {
+		ctx := propagator.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		ctx, span := tracer.Start(ctx, c.Request.Method+" "+c.FullPath())
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Set(spanContextKey, span)
+
+		c.Next()
+	}
+}
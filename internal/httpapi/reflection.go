@@ -1,23 +1,32 @@
 package httpapi
 
 import (
-	"github.com/golang/glog"
-	"net"
+	"net/netip"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/logging"
 )
 
-// HandleGetIP is a gin HTTP handler that gather's the source IP from an
-// incoming HTTP request and returns it in the response body.
-func HandleGetIP(ctx *gin.Context) {
-	ip, port, err := net.SplitHostPort(ctx.Request.RemoteAddr)
-	if err != nil {
-		glog.Error(err.Error())
-		ctx.JSON(500, gin.H{"result": "internal server error"})
-		return
-	}
+// HandleGetIP returns a gin HTTP handler that gathers the client's address
+// (honoring X-Forwarded-For/X-Real-IP if the request came through one of
+// trustedProxies) from an incoming HTTP request and returns it in the
+// response body.
+func HandleGetIP(logger logging.Logger, trustedProxies []netip.Prefix) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestLogger := logging.WithRequestID(logger, RequestIDFromGinContext(ctx))
 
-	glog.Info("Incoming request /getip:" + ip + ":" + port)
-	// Only return the IP, even though we have their source ephemeral port.
-	ctx.JSON(200, gin.H{"ip": ip})
+		ip, err := ClientIP(ctx.Request, trustedProxies)
+		if err != nil {
+			requestLogger.Error("failed to determine client IP", logging.Err(err))
+			ctx.JSON(500, gin.H{"result": "internal server error"})
+			return
+		}
+
+		requestLogger.Info("Incoming request /getip",
+			logging.String("event", "getip"),
+			logging.String("remote_ip", ip.String()),
+		)
+		ctx.JSON(200, gin.H{"ip": ip.String()})
+	}
 }
@@ -1,67 +1,234 @@
 package httpapi
 
 import (
-	"bufio"
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"github.com/golang/glog"
+	"io"
 	"io/ioutil"
-	"net"
 	"net/http"
+	"net/netip"
+	"strconv"
 	"time"
 
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/auth"
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/challenge"
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/logging"
 	"github.com/FugitiveTheGame/Fugitive3dServerRepository/srvrepo"
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/srvrepo/geoip"
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/srvrepo/healthcheck"
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 )
 
+// streamUpgrader upgrades /servers/ws connections. Origin checking is left to
+// the caller's auth/proxy layer, same as the rest of this API.
+var streamUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
 // ServerController is an HTTP API controller for server resources.
 type ServerController struct {
-	repository *srvrepo.ServerRepository
+	repository     *srvrepo.ServerRepository
+	checker        healthcheck.HealthChecker
+	geoResolver    geoip.Resolver
+	challenges     *challenge.Listener
+	trustedProxies []netip.Prefix
+	logger         logging.Logger
 }
 
 // NewServerController constructs a new Controller for controlling
-// server resources.
-func NewServerController(repository *srvrepo.ServerRepository) *ServerController {
+// server resources. The given HealthChecker is probed against every
+// registering server before it is admitted into the repository. The given
+// geoip.Resolver enriches each registering server's IP with a GeoLocation;
+// pass geoip.NoopResolver{} to disable enrichment. The given challenge.Listener
+// issues and verifies the reachability nonces that HandleRegister requires
+// before admitting a server. trustedProxies names the proxies (see
+// ParseTrustedProxies) allowed to set X-Forwarded-For/X-Real-IP on an
+// incoming request; it may be nil to trust no proxy.
+func NewServerController(repository *srvrepo.ServerRepository, checker healthcheck.HealthChecker, geoResolver geoip.Resolver, challenges *challenge.Listener, trustedProxies []netip.Prefix, logger logging.Logger) *ServerController {
 	return &ServerController{
-		repository: repository,
+		repository:     repository,
+		checker:        checker,
+		geoResolver:    geoResolver,
+		challenges:     challenges,
+		trustedProxies: trustedProxies,
+		logger:         logger,
+	}
+}
+
+// clientIP returns the real client address for ctx's request, honoring
+// X-Forwarded-For/X-Real-IP if the request came through a trusted proxy.
+func (c *ServerController) clientIP(ctx *gin.Context) (netip.Addr, error) {
+	return ClientIP(ctx.Request, c.trustedProxies)
+}
+
+// authorizeClaims verifies that the request's verified token claims (set on
+// the gin context by auth.RequireToken) permit acting on serverAddr: the
+// token's AllowedIP must match serverAddr's IP, and its ServerID, if set,
+// must match serverAddr's ID. Without this, any token with the "register"
+// scope could register, update, or remove a server other than the one it
+// was issued for.
+func (c *ServerController) authorizeClaims(ctx *gin.Context, serverAddr srvrepo.ServerAddress) error {
+	claims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("missing token claims")
+	}
+
+	if claims.AllowedIP != serverAddr.IP.String() {
+		return fmt.Errorf("token is not authorized for this server's address")
 	}
+
+	if claims.ServerID != "" && claims.ServerID != serverAddr.String() {
+		return fmt.Errorf("token is not authorized for this server ID")
+	}
+
+	return nil
+}
+
+// enrichGeo resolves serverData.Geo from its IP, logging (but not failing the
+// request on) a resolution error, since GeoIP enrichment is best-effort.
+func (c *ServerController) enrichGeo(logger logging.Logger, serverData *srvrepo.Server) {
+	geo, err := c.geoResolver.Resolve(serverData.IP)
+	if err != nil {
+		logger.Warn("geoip enrichment failed", logging.Err(err))
+		return
+	}
+
+	serverData.Geo = geo
+}
+
+// loggerFor returns a request-scoped logger carrying this request's ID, so
+// that every log line from a handler can be correlated back to the request
+// that produced it.
+func (c *ServerController) loggerFor(ctx *gin.Context) logging.Logger {
+	return logging.WithRequestID(c.logger, RequestIDFromGinContext(ctx))
 }
 
 // HandleList is a gin HTTP handler that returns a list of the registered
-// servers in the response body.
+// servers in the response body. The list can be narrowed with ?country= and
+// ?continent= (ISO codes, matched against each server's resolved
+// GeoLocation), and ordered nearest-first with
+// ?sort=distance&lat=..&lon=...
 func (c *ServerController) HandleList(ctx *gin.Context) {
 	serverList := c.repository.List()
 
+	serverList = srvrepo.FilterByRegion(serverList, ctx.Query("country"), ctx.Query("continent"))
+
+	if ctx.Query("sort") == "distance" {
+		lat, latErr := strconv.ParseFloat(ctx.Query("lat"), 64)
+		lon, lonErr := strconv.ParseFloat(ctx.Query("lon"), 64)
+		if latErr == nil && lonErr == nil {
+			srvrepo.SortByDistance(serverList, lat, lon)
+		}
+	}
+
 	// Send server list to client
 	ctx.JSON(http.StatusOK, serverList)
 }
 
-// HandleRegister is a gin HTTP handler that allows servers to update
-// their registration to keep things fresh
+// HandleStream streams the repository's Events to the client as
+// Server-Sent Events as they're published, so a lobby browser can get
+// near-instant updates instead of polling /servers.
+func (c *ServerController) HandleStream(ctx *gin.Context) {
+	logger := c.loggerFor(ctx)
+
+	ctx.Writer.Header().Set("Content-Type", "text/event-stream")
+	ctx.Writer.Header().Set("Cache-Control", "no-cache")
+	ctx.Writer.Header().Set("Connection", "keep-alive")
+
+	events := c.repository.Subscribe(ctx.Request.Context())
+
+	logger.Info("client subscribed to live server list updates", logging.String("event", "stream_connected"))
+
+	ctx.Stream(func(w io.Writer) bool {
+		event, ok := <-events
+		if !ok {
+			return false
+		}
+
+		data, err := json.Marshal(event)
+		if err != nil {
+			logger.Warn("failed marshalling event for /servers/stream subscriber", logging.Err(err))
+			return false
+		}
+
+		ctx.SSEvent(string(event.Type), json.RawMessage(data))
+		return true
+	})
+}
+
+// HandleWS upgrades the request to a WebSocket and streams the repository's
+// Events to the client as they're published.
+func (c *ServerController) HandleWS(ctx *gin.Context) {
+	logger := c.loggerFor(ctx)
+
+	conn, err := streamUpgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		logger.Error("failed to upgrade /servers/ws to a websocket", logging.Err(err))
+		return
+	}
+	defer conn.Close()
+
+	events := c.repository.Subscribe(ctx.Request.Context())
+
+	logger.Info("client subscribed to live server list updates", logging.String("event", "ws_connected"))
+
+	for event := range events {
+		if err := conn.WriteJSON(event); err != nil {
+			logger.Warn("failed writing to /servers/ws subscriber, disconnecting", logging.Err(err))
+			return
+		}
+	}
+}
+
+// HandleUpdate is a gin HTTP handler that renews an already-registered
+// server's heartbeat. It requires the server to already exist in the
+// repository, since it performs no health check or challenge verification
+// of its own; a server must go through HandleRegister first.
 func (c *ServerController) HandleUpdate(ctx *gin.Context) {
-	requestAddr, _ := srvrepo.ParseServerAddress(ctx.Request.RemoteAddr)
+	logger := c.loggerFor(ctx)
+
+	requestIP, err := c.clientIP(ctx)
+	if err != nil {
+		logger.Error("failed to determine client IP", logging.Err(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"result": "internal server error"})
+		return
+	}
+
 	var serverData srvrepo.Server
 
 	body, _ := ioutil.ReadAll(ctx.Request.Body)
 	if err := json.Unmarshal(body, &serverData); err != nil {
-		glog.Error("Server Update: invalid request JSON")
+		logger.Error("Server Update: invalid request JSON")
 		ctx.JSON(http.StatusBadRequest, gin.H{"result": "invalid request JSON"})
 	}
 
 	serverAddr, err := srvrepo.ParseServerAddress(ctx.Param("server_id"))
 	if err != nil {
-		glog.Error("Server Update: invalid server ID")
+		logger.Error("Server Update: invalid server ID")
 		// 404, since the ID is a URL param
 		ctx.JSON(http.StatusBadRequest, gin.H{"result": "invalid server ID"})
 		return
 	}
 
-	/*
-		Don't check to see if they existed already, just note whether or not they exist.
-		we need to handle the case where they've registered but the repo restarted for some reason.
-	*/
-	existed := c.repository.Has(srvrepo.ServerID(serverAddr.String()))
+	if err := c.authorizeClaims(ctx, serverAddr); err != nil {
+		logger.Error("Server Update: token not authorized for this server", logging.Err(err))
+		ctx.JSON(http.StatusForbidden, gin.H{"result": err.Error()})
+		return
+	}
+
+	// HandleUpdate only renews an existing registration's heartbeat; a
+	// server must go through HandleRegister (POST) first so it's actually
+	// health-checked and challenge-verified. Without this, a "register"
+	// scoped token could PUT a brand-new registration for any port at its
+	// allowed IP with no reachability proof at all.
+	if !c.repository.Has(srvrepo.ServerID(serverAddr.String())) {
+		logger.Error("Server Update: server is not yet registered", logging.String("server_id", serverAddr.String()))
+		ctx.JSON(http.StatusNotFound, gin.H{"result": "server not registered, register via POST /servers/:server_id first"})
+		return
+	}
 
 	// Make sure that the provided address is what's set in the data, so that
 	// the server data and ID match.
@@ -71,39 +238,40 @@ func (c *ServerController) HandleUpdate(ctx *gin.Context) {
 	serverData.Seen()
 
 	if err := serverData.Validate(); err != nil {
-		glog.Error("error during input validation: %v\n", err)
+		logger.Error("error during input validation", logging.Err(err))
 		ctx.JSON(http.StatusBadRequest, gin.H{"result": err.Error()})
 		return
 	}
 
-	if !serverData.IP.Equal(requestAddr.IP) {
-		glog.Info("Server Update: request IP address does not match client IP address")
+	if serverData.IP != requestIP {
 		err := fmt.Errorf("request IP address does not match client IP address")
 
-		glog.Error("error during request validation: %v\n", err)
+		logger.Error("Server Update: request IP address does not match client IP address", logging.Err(err))
 		ctx.JSON(http.StatusForbidden, gin.H{"result": err.Error()})
 		return
 	}
 
-	glog.Infof("A server is attempting update: %s:%d", serverData.IP, serverData.Port)
+	logger.Info("A server is attempting update", logging.String("event", "update_attempt"), logging.String("server_id", string(serverData.ID())))
 
-	existed, err = c.repository.Register(serverData)
-	if err != nil {
-		glog.Errorf("error registering server: %v\n", err)
+	c.enrichGeo(logger, &serverData)
+
+	if _, err := c.repository.Register(serverData); err != nil {
+		logger.Error("error registering server", logging.Err(err))
 		ctx.JSON(http.StatusInternalServerError, gin.H{"result": "internal server error"})
-	} else if existed {
-		glog.Infof("This server updated: %s:%d", serverData.IP, serverData.Port)
-		ctx.JSON(http.StatusAccepted, gin.H{"result": "updated"})
-	} else {
-		glog.Info("New server registered via update: %s:%d", serverData.IP, serverData.Port)
-		ctx.JSON(http.StatusCreated, gin.H{"result": "registered"})
+		return
 	}
+
+	logger.Info("This server updated", logging.String("event", "update_updated"), logging.String("server_id", string(serverData.ID())))
+	ctx.JSON(http.StatusAccepted, gin.H{"result": "updated"})
 }
 
-// HandleRegister is a gin HTTP handler that allows servers to register
-// themselves in the repository. This call will also dial back to the port
-// being registered and confirm that the port is accessible.
-func (c *ServerController) HandleRegister(ctx *gin.Context) {
+// HandleChallenge is a gin HTTP handler that issues a reachability nonce for
+// a registering server. The server must echo the nonce back to the
+// repository's challenge listener over UDP, from the same address it's
+// registering, before HandleRegister will admit it.
+func (c *ServerController) HandleChallenge(ctx *gin.Context) {
+	logger := c.loggerFor(ctx)
+
 	serverAddr, err := srvrepo.ParseServerAddress(ctx.Param("server_id"))
 	if err != nil {
 		// 404, since the ID is a URL param
@@ -111,119 +279,169 @@ func (c *ServerController) HandleRegister(ctx *gin.Context) {
 		return
 	}
 
-	glog.Infof("A server is attempting registration: %s:%d", serverAddr.IP, serverAddr.Port)
+	if err := c.authorizeClaims(ctx, serverAddr); err != nil {
+		logger.Error("token not authorized for this server", logging.Err(err))
+		ctx.JSON(http.StatusForbidden, gin.H{"result": err.Error()})
+		return
+	}
 
-	destinationAddress, _ := net.ResolveUDPAddr("udp", serverAddr.String())
-	connection, err := net.DialUDP("udp", nil, destinationAddress)
+	requestIP, err := c.clientIP(ctx)
 	if err != nil {
-		glog.Fatal(err)
-		ctx.JSON(http.StatusPreconditionFailed, gin.H{"result": "Repository could not ping you."})
+		logger.Error("failed to determine client IP", logging.Err(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"result": "internal server error"})
+		return
 	}
-	defer connection.Close()
 
-	err = connection.SetReadDeadline(time.Now().Add(time.Second * 5))
+	if serverAddr.IP != requestIP {
+		err := fmt.Errorf("request IP address does not match client IP address")
+
+		logger.Error("error during request validation", logging.Err(err))
+		ctx.JSON(http.StatusForbidden, gin.H{"result": err.Error()})
+		return
+	}
+
+	nonce, err := c.challenges.Issue(serverAddr.AddrPort())
 	if err != nil {
-		glog.Error("Error SetReadDeadline")
+		logger.Error("failed to issue challenge nonce", logging.Err(err), logging.String("server_id", serverAddr.String()))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"result": "internal server error"})
+		return
 	}
 
-	glog.Info("Pinging new server...")
+	logger.Info("issued challenge nonce", logging.String("event", "challenge_issued"), logging.String("server_id", serverAddr.String()))
+	ctx.JSON(http.StatusOK, gin.H{
+		"nonce": nonce,
+		"addr":  c.challenges.Addr().String(),
+	})
+}
+
+// HandleRegister is a gin HTTP handler that allows servers to register
+// themselves in the repository. This call will also probe the registering
+// address with the controller's HealthChecker and require a verified
+// challenge nonce (see HandleChallenge), rejecting the registration if the
+// server isn't actually reachable.
+func (c *ServerController) HandleRegister(ctx *gin.Context) {
+	logger := c.loggerFor(ctx)
+
+	serverAddr, err := srvrepo.ParseServerAddress(ctx.Param("server_id"))
+	if err != nil {
+		// 406 for a server ID in the URL that doesn't parse, as in HandleChallenge
+		ctx.JSON(http.StatusNotAcceptable, gin.H{"result": "invalid server ID"})
+		return
+	}
 
-	// We're sending 10 of these because of UDP
-	// Only one actually needs to be received
-	var buffer bytes.Buffer
-	buffer.WriteString("ping")
-	for ii := 0; ii < 10; ii++ {
-		connection.Write(buffer.Bytes())
+	if err := c.authorizeClaims(ctx, serverAddr); err != nil {
+		logger.Error("token not authorized for this server", logging.Err(err))
+		ctx.JSON(http.StatusForbidden, gin.H{"result": err.Error()})
+		return
 	}
 
-	glog.Info("Waiting for reponse...")
+	logger.Info("A server is attempting registration", logging.String("event", "register_attempt"), logging.String("server_id", serverAddr.String()))
 
-	// Wait and read out the response from the game server
-	readBuff := make([]byte, 8)
-	_, err = bufio.NewReader(connection).Read(readBuff)
+	if err := c.checker.Probe(ctx.Request.Context(), serverAddr); err != nil {
+		logger.Error("health check failed", logging.Err(err), logging.String("server_id", serverAddr.String()))
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{"result": "repository could not reach your server, is your port forwarded?"})
+		return
+	}
 
-	if err != nil {
-		ctx.JSON(http.StatusGatewayTimeout, gin.H{"result": "no ping response received, is your port not properly forwarded?"})
+	nonce := ctx.Query("nonce")
+	if nonce == "" || !c.challenges.Verified(nonce, serverAddr.AddrPort()) {
+		logger.Error("registration attempted without a verified challenge nonce", logging.String("server_id", serverAddr.String()))
+		ctx.JSON(http.StatusPreconditionRequired, gin.H{"result": "reachability not verified, request a challenge nonce from /servers/:server_id/challenge first"})
 		return
 	}
-	response := string(readBuff[0:4])
 
-	glog.Infof("Response received: '%s'", response)
+	var serverData srvrepo.Server
+	body, _ := ioutil.ReadAll(ctx.Request.Body)
+	if err := json.Unmarshal(body, &serverData); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"result": "invalid request JSON"})
+	}
 
-	// If the response is all good, handle the registration
-	if response == "pong" {
-		var serverData srvrepo.Server
-		body, _ := ioutil.ReadAll(ctx.Request.Body)
-		if err := json.Unmarshal(body, &serverData); err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"result": "invalid request JSON"})
-		}
+	// Make sure that the provided address is what's set in the data, so that
+	// the server data and ID match.
+	serverData.ServerAddress = serverAddr
 
-		// Make sure that the provided address is what's set in the data, so that
-		// the server data and ID match.
-		serverData.ServerAddress = serverAddr
+	// Update the last-seen value to "now"
+	serverData.Seen()
 
-		// Update the last-seen value to "now"
-		serverData.Seen()
+	if err := serverData.Validate(); err != nil {
+		logger.Error("error during input validation", logging.Err(err))
+		ctx.JSON(http.StatusBadRequest, gin.H{"result": err.Error()})
+		return
+	}
 
-		if err := serverData.Validate(); err != nil {
-			glog.Errorf("error during input validation: %v\n", err)
-			ctx.JSON(http.StatusBadRequest, gin.H{"result": err.Error()})
-			return
-		}
+	requestIP, err := c.clientIP(ctx)
+	if err != nil {
+		logger.Error("failed to determine client IP", logging.Err(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"result": "internal server error"})
+		return
+	}
 
-		requestAddr, _ := srvrepo.ParseServerAddress(ctx.Request.RemoteAddr)
-		if !serverData.IP.Equal(requestAddr.IP) {
-			err := fmt.Errorf("request IP address does not match client IP address")
+	if serverData.IP != requestIP {
+		err := fmt.Errorf("request IP address does not match client IP address")
 
-			glog.Errorf("error during request validation: %v\n", err)
-			ctx.JSON(http.StatusForbidden, gin.H{"result": err.Error()})
-			return
-		}
+		logger.Error("error during request validation", logging.Err(err))
+		ctx.JSON(http.StatusForbidden, gin.H{"result": err.Error()})
+		return
+	}
 
-		_, err = c.repository.Register(serverData)
-		if err != nil {
-			glog.Errorf("error registering server: %v\n", err)
-			ctx.JSON(http.StatusInternalServerError, gin.H{"result": "internal server error"})
-		} else {
-			ctx.JSON(http.StatusOK, gin.H{"result": "registration complete"})
-		}
-	} else {
-		glog.Errorf("error registering server, bad ping response: %s\n", response)
-		ctx.JSON(http.StatusNotAcceptable, gin.H{"result": "Bad ping response"})
+	c.enrichGeo(logger, &serverData)
+
+	verifiedAt := srvrepo.NewVerifiedAt(time.Now())
+	serverData.VerifiedAt = &verifiedAt
+
+	if _, err = c.repository.Register(serverData); err != nil {
+		logger.Error("error registering server", logging.Err(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"result": "internal server error"})
+		return
 	}
+
+	ctx.JSON(http.StatusOK, gin.H{"result": "registration complete"})
 }
 
 // HandleRemove is a gin HTTP handler that allows servers to remove themselves
 // from the repository.
 func (c *ServerController) HandleRemove(ctx *gin.Context) {
-	requestAddr, _ := srvrepo.ParseServerAddress(ctx.Request.RemoteAddr)
+	logger := c.loggerFor(ctx)
+
+	requestIP, err := c.clientIP(ctx)
+	if err != nil {
+		logger.Error("failed to determine client IP", logging.Err(err))
+		ctx.JSON(http.StatusInternalServerError, gin.H{"result": "internal server error"})
+		return
+	}
 
 	serverAddr, err := srvrepo.ParseServerAddress(ctx.Param("server_id"))
 	if err != nil {
-		glog.Errorf("Invalid server ID: %v", err)
+		logger.Error("invalid server ID", logging.Err(err))
 		// 404, since the ID is a URL param
 		ctx.JSON(http.StatusNotFound, gin.H{"result": "invalid server ID"})
 		return
 	}
 
-	if !serverAddr.IP.Equal(requestAddr.IP) {
+	if err := c.authorizeClaims(ctx, serverAddr); err != nil {
+		logger.Error("token not authorized for this server", logging.Err(err))
+		ctx.JSON(http.StatusForbidden, gin.H{"result": err.Error()})
+		return
+	}
+
+	if serverAddr.IP != requestIP {
 		err := fmt.Errorf("request IP address does not match client IP address")
 
-		glog.Errorf("error during request validation: %v", err)
+		logger.Error("error during request validation", logging.Err(err))
 		ctx.JSON(http.StatusForbidden, gin.H{"result": err.Error()})
 		return
 	}
 
-	glog.Info("A server is being removed.")
+	logger.Info("A server is being removed", logging.String("event", "remove_attempt"), logging.String("server_id", serverAddr.String()))
 
 	exists := c.repository.Remove(srvrepo.ServerID(serverAddr.String()))
 
 	if !exists {
-		glog.Warning("The server was not found.")
+		logger.Warn("The server was not found", logging.String("server_id", serverAddr.String()))
 		ctx.JSON(http.StatusNotFound, gin.H{"result": "failure"})
 		return
 	}
 
-	glog.Infof("This server is being removed: %s", serverAddr.String())
+	logger.Info("This server is being removed", logging.String("event", "remove_success"), logging.String("server_id", serverAddr.String()))
 	ctx.JSON(200, gin.H{"result": "success"})
 }
@@ -0,0 +1,24 @@
+package logging
+
+import "context"
+
+// contextKey is an unexported type for context keys defined in this package,
+// preventing collisions with keys defined elsewhere.
+type contextKey string
+
+const requestIDContextKey contextKey = "request_id"
+
+// ContextWithRequestID returns a copy of ctx carrying the given request ID,
+// so that code several layers away from the originating HTTP handler (e.g.
+// an outbound health probe) can still log with it attached.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored by ContextWithRequestID,
+// or the empty string if none is present.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey).(string)
+
+	return requestID
+}
@@ -0,0 +1,84 @@
+// Package logging provides the structured Logger interface threaded through
+// the repository's constructors, replacing the previous mix of fmt.Println,
+// glog, and a process-global zerolog logger with a single injected
+// dependency backed by go.uber.org/zap.
+package logging
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// Field is a structured log attribute, e.g. logging.String("server_id", id).
+type Field = zap.Field
+
+// String and Err build Fields, re-exported so callers don't need their own
+// go.uber.org/zap import just to log.
+var (
+	String = zap.String
+	Err    = zap.Error
+)
+
+// Logger is the structured logging interface threaded through constructors
+// such as NewServerController and NewServerRepository, so call sites log
+// through an injected dependency - rather than a package global - and every
+// entry point emits the same typed fields.
+type Logger interface {
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warn(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+
+	// With returns a Logger that always includes the given fields, e.g. a
+	// request ID.
+	With(fields ...Field) Logger
+}
+
+// WithRequestID returns logger with the given request ID attached as the
+// "request_id" field, so every line it emits can be correlated back to the
+// HTTP request that produced it. logger is returned unchanged if requestID
+// is empty.
+func WithRequestID(logger Logger, requestID string) Logger {
+	if requestID == "" {
+		return logger
+	}
+
+	return logger.With(String("request_id", requestID))
+}
+
+// zapLogger adapts a *zap.Logger to the Logger interface.
+type zapLogger struct {
+	z *zap.Logger
+}
+
+func (l *zapLogger) Debug(msg string, fields ...Field) { l.z.Debug(msg, fields...) }
+func (l *zapLogger) Info(msg string, fields ...Field)  { l.z.Info(msg, fields...) }
+func (l *zapLogger) Warn(msg string, fields ...Field)  { l.z.Warn(msg, fields...) }
+func (l *zapLogger) Error(msg string, fields ...Field) { l.z.Error(msg, fields...) }
+
+func (l *zapLogger) With(fields ...Field) Logger {
+	return &zapLogger{z: l.z.With(fields...)}
+}
+
+// NewProduction returns a Logger configured for production use: JSON
+// encoded, info level and above.
+func NewProduction() (Logger, error) {
+	z, err := zap.NewProduction()
+	if err != nil {
+		return nil, fmt.Errorf("building production logger: %w", err)
+	}
+
+	return &zapLogger{z: z}, nil
+}
+
+// NewDevelopment returns a Logger configured for local development:
+// human-readable console output, debug level and above.
+func NewDevelopment() (Logger, error) {
+	z, err := zap.NewDevelopment()
+	if err != nil {
+		return nil, fmt.Errorf("building development logger: %w", err)
+	}
+
+	return &zapLogger{z: z}, nil
+}
@@ -0,0 +1,108 @@
+// Package metrics defines the Prometheus instrumentation exposed at
+// /metrics, along with the gin middleware and ServerRepository hooks that
+// feed it.
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Recorder holds the process's Prometheus collectors and the methods used to
+// update them. A single Recorder is shared between the httpapi middleware
+// and the ServerRepository, so every registration path reports through the
+// same metrics regardless of which Store backend is configured.
+type Recorder struct {
+	registerTotal prometheus.Counter
+	removeTotal   prometheus.Counter
+	pruneTotal    prometheus.Counter
+
+	serversCurrent       prometheus.Gauge
+	serversByGameVersion *prometheus.GaugeVec
+
+	handlerDuration *prometheus.HistogramVec
+}
+
+// NewRecorder registers a new set of collectors with reg and returns a
+// pointer to the Recorder wrapping them. Pass prometheus.DefaultRegisterer
+// to expose them via promhttp.Handler().
+func NewRecorder(reg prometheus.Registerer) *Recorder {
+	factory := promauto.With(reg)
+
+	return &Recorder{
+		registerTotal: factory.NewCounter(prometheus.CounterOpts{
+			Name: "register_total",
+			Help: "Total number of server registration requests handled.",
+		}),
+		removeTotal: factory.NewCounter(prometheus.CounterOpts{
+			Name: "remove_total",
+			Help: "Total number of server removal requests handled.",
+		}),
+		pruneTotal: factory.NewCounter(prometheus.CounterOpts{
+			Name: "prune_total",
+			Help: "Total number of servers removed by the stale pruner.",
+		}),
+		serversCurrent: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "servers_current",
+			Help: "Number of servers currently registered.",
+		}),
+		serversByGameVersion: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "servers_by_game_version",
+			Help: "Number of servers currently registered, by game version.",
+		}, []string{"game_version"}),
+		handlerDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_handler_duration_seconds",
+			Help:    "Latency of HTTP handlers, in seconds.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "path"}),
+	}
+}
+
+// RecordRegister increments register_total.
+func (r *Recorder) RecordRegister() {
+	r.registerTotal.Inc()
+}
+
+// RecordRemove increments remove_total.
+func (r *Recorder) RecordRemove() {
+	r.removeTotal.Inc()
+}
+
+// RecordPrune increments prune_total by n, the number of servers a single
+// pruning sweep removed.
+func (r *Recorder) RecordPrune(n int) {
+	r.pruneTotal.Add(float64(n))
+}
+
+// SetServersCurrent sets the servers_current gauge to n.
+func (r *Recorder) SetServersCurrent(n int) {
+	r.serversCurrent.Set(float64(n))
+}
+
+// SetServersByGameVersion replaces the servers_by_game_version gauge vector
+// with counts, keyed by game version.
+func (r *Recorder) SetServersByGameVersion(counts map[int]int) {
+	r.serversByGameVersion.Reset()
+
+	for version, count := range counts {
+		r.serversByGameVersion.WithLabelValues(strconv.Itoa(version)).Set(float64(count))
+	}
+}
+
+// Instrument returns a gin middleware that observes each request's latency
+// into http_handler_duration_seconds, labeled by method and route.
+func Instrument(recorder *Recorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		recorder.handlerDuration.
+			WithLabelValues(c.Request.Method, c.FullPath()).
+			Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,63 @@
+// Package pubsub provides a small generic fan-out broker used to push
+// incremental updates to any number of subscribers without polling.
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// subscriberQueueSize bounds how many undelivered values a single Subscribe
+// channel may hold before the Broker starts dropping values for that
+// subscriber instead of blocking Publish on it.
+const subscriberQueueSize = 16
+
+// Broker fans values of type T out to any number of subscribers. A
+// subscriber that isn't keeping up has values dropped for it rather than
+// blocking Publish. The zero value is ready to use.
+type Broker[T any] struct {
+	mu          sync.Mutex
+	subscribers map[chan T]struct{}
+}
+
+// Subscribe registers for values published via Publish, returning a channel
+// of them. The channel is unregistered and closed automatically once ctx is
+// done, so callers should range over it rather than reading in a loop
+// guarded by a separate select on ctx.Done().
+func (b *Broker[T]) Subscribe(ctx context.Context) <-chan T {
+	ch := make(chan T, subscriberQueueSize)
+
+	b.mu.Lock()
+	if b.subscribers == nil {
+		b.subscribers = make(map[chan T]struct{})
+	}
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		delete(b.subscribers, ch)
+		b.mu.Unlock()
+
+		close(ch)
+	}()
+
+	return ch
+}
+
+// Publish delivers value to every current subscriber, dropping it for any
+// subscriber whose channel is full instead of blocking on them. Publish
+// never blocks the caller.
+func (b *Broker[T]) Publish(value T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- value:
+		default:
+		}
+	}
+}
@@ -1,84 +1,241 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"io"
 	"net"
 	"net/http"
+	"net/netip"
 	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/auth"
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/challenge"
 	"github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/httpapi"
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/logging"
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/metrics"
 	"github.com/FugitiveTheGame/Fugitive3dServerRepository/srvrepo"
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/srvrepo/geoip"
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/srvrepo/healthcheck"
 	"github.com/gin-contrib/gzip"
 	"github.com/gin-gonic/gin"
-	"github.com/szuecs/gin-glog"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
-// test it out
-// curl -d '{"name":"special server", "ip":"1.2.3.5", "port":"45677"}' -H "Content-Type: application/json" -X POST localhost:8080/register
+// loadSecret reads a shared HMAC secret from the given file path, trimming
+// surrounding whitespace/newlines.
+func loadSecret(path string) ([]byte, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading JWT secret file: %w", err)
+	}
 
-// pruneServers takes a threshold duration for server age to prune old servers,
-// running via an infinite ticker that ticks at half the duration of the given
-// threshold.
-func pruneServers(repository *srvrepo.ServerRepository, threshold time.Duration) {
-	// The interval is half the treshold
-	interval := threshold / 2
+	return []byte(strings.TrimSpace(string(raw))), nil
+}
 
-	for range time.Tick(interval) {
-		repository.Prune(threshold)
+// healthCheckTimeout and healthCheckRetries bound how long (and how many
+// times) we're willing to wait for a registering server to respond to a
+// health probe before rejecting the registration.
+const (
+	healthCheckTimeout = 5 * time.Second
+	healthCheckRetries = 2
+)
+
+// newHealthChecker builds the HealthChecker selected by the -healthcheck CLI
+// flag.
+func newHealthChecker(kind string, logger logging.Logger) (healthcheck.HealthChecker, error) {
+	switch kind {
+	case "udp":
+		return healthcheck.NewUDPChecker(healthCheckTimeout, healthCheckRetries, logger), nil
+	case "tcp":
+		return healthcheck.NewTCPChecker(healthCheckTimeout), nil
+	case "http":
+		return healthcheck.NewHTTPChecker("/healthz", healthCheckTimeout), nil
+	case "none":
+		return healthcheck.NoopChecker{}, nil
+	default:
+		return nil, fmt.Errorf("unknown healthcheck kind %q (want udp, tcp, http, or none)", kind)
 	}
 }
 
+// newGeoResolver builds the geoip.Resolver selected by the -geoip-db CLI
+// flag. An empty path disables enrichment entirely via geoip.NoopResolver.
+func newGeoResolver(dbPath string) (geoip.Resolver, error) {
+	if dbPath == "" {
+		return geoip.NoopResolver{}, nil
+	}
+
+	return geoip.NewMaxMindResolver(dbPath)
+}
+
+// newLogger builds the process-wide Logger selected by the -log-mode CLI
+// flag: "production" emits JSON suitable for log aggregation, "development"
+// emits human-readable console output suited to local debugging.
+func newLogger(mode string) (logging.Logger, error) {
+	switch mode {
+	case "production":
+		return logging.NewProduction()
+	case "development":
+		return logging.NewDevelopment()
+	default:
+		return nil, fmt.Errorf("unknown log mode %q (want production or development)", mode)
+	}
+}
+
+// test it out ($TOKEN from `fugitivectl issue-token`; the server must echo the
+// returned nonce over UDP to the returned addr before registering)
+// curl -H "Authorization: Bearer $TOKEN" -X POST localhost:8080/servers/1.2.3.5:45677/challenge
+// curl -d '{"name":"special server"}' -H "Content-Type: application/json" -H "Authorization: Bearer $TOKEN" -X POST "localhost:8080/servers/1.2.3.5:45677?nonce=$NONCE"
+
 func main() {
 	// Allow users to provide arguments on the CLI
 	var ipAddr string
 	var portNum int
 	var staleThreshold int
-	var logPath string
+	var logMode string
+	var healthCheckKind string
+	var jwtSecretFile string
+	var storeSpec string
+	var geoipDBPath string
+	var challengePortMin int
+	var challengePortMax int
+	var challengeTimeout time.Duration
+	var trustedProxiesSpec string
 
 	flag.StringVar(&ipAddr, "a", "0.0.0.0", "IP address for repository  to listen on")
 	flag.IntVar(&portNum, "p", 8080, "TCP port for repository to listen on")
 	flag.IntVar(&staleThreshold, "s", 30, "Duration (in seconds) before a server is marked stale")
-	flag.StringVar(&logPath, "l", "gin-server.log", "Path to write log file to")
+	flag.StringVar(&logMode, "log-mode", "production", "Logging config: production (JSON) or development (console)")
+	flag.StringVar(&healthCheckKind, "healthcheck", "udp", "Liveness probe used before accepting registrations (udp, tcp, http, or none)")
+	flag.StringVar(&jwtSecretFile, "jwt-secret-file", "", "Path to a file containing the shared HMAC secret used to sign/verify registration tokens (required)")
+	flag.StringVar(&storeSpec, "store", "memory", "Storage backend: memory, redis://host:port, or bolt:///path/to/db")
+	flag.StringVar(&geoipDBPath, "geoip-db", "", "Path to a MaxMind GeoLite2 City database; enables GeoIP enrichment of registered servers if set")
+	flag.IntVar(&challengePortMin, "challenge-port-min", 24000, "Lowest UDP port the reachability challenge listener may bind to")
+	flag.IntVar(&challengePortMax, "challenge-port-max", 24099, "Highest UDP port the reachability challenge listener may bind to")
+	flag.DurationVar(&challengeTimeout, "challenge-timeout", challenge.DefaultTimeout, "How long an issued challenge nonce remains valid before it must be retried")
+	flag.StringVar(&trustedProxiesSpec, "trusted-proxies", "", "Comma-separated CIDRs of reverse proxies allowed to set X-Forwarded-For/X-Real-IP (e.g. running behind Cloudflare or an ingress)")
 	flag.Parse()
 
 	serveAddr := net.JoinHostPort(ipAddr, strconv.Itoa(portNum))
 
-	s := fmt.Sprintf("Server starting with arguments: %s staleThreshold=%v", serveAddr, staleThreshold)
-	fmt.Println(s)
+	logger, err := newLogger(logMode)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
-	router := initApp(staleThreshold, logPath)
+	logger.Info("server starting", logging.String("addr", serveAddr), logging.String("stale_threshold", strconv.Itoa(staleThreshold)))
 
-	http.ListenAndServe(serveAddr, router)
-}
+	checker, err := newHealthChecker(healthCheckKind, logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
-func initApp(staleThreshold int, logPath string) http.Handler {
-	// Log to a file (overwrite) and stdout
-	f, _ := os.Create(logPath)
+	trustedProxies, err := httpapi.ParseTrustedProxies(trustedProxiesSpec)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
-	// TODO: This is overriding globally. We should likely use a better scope.
-	gin.DefaultWriter = io.MultiWriter(f, os.Stdout)
+	if jwtSecretFile == "" {
+		fmt.Fprintln(os.Stderr, "-jwt-secret-file is required")
+		os.Exit(1)
+	}
 
-	router := gin.Default()
-	router.Use(gzip.Gzip(gzip.DefaultCompression))
-	router.Use(ginglog.Logger(3 * time.Second))
+	secret, err := loadSecret(jwtSecretFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	signer := auth.NewSigner(secret)
+
+	store, err := srvrepo.OpenStore(storeSpec, time.Duration(staleThreshold)*time.Second, logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	geoResolver, err := newGeoResolver(geoipDBPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if closer, ok := geoResolver.(*geoip.MaxMindResolver); ok {
+		defer closer.Close()
+	}
+
+	challenges, err := challenge.Listen(challengePortMin, challengePortMax, challengeTimeout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer challenges.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	recorder := metrics.NewRecorder(prometheus.DefaultRegisterer)
+
+	router, repository := initApp(staleThreshold, checker, signer, store, geoResolver, challenges, recorder, trustedProxies, logger)
+
+	go repository.Run(ctx)
+	defer repository.Close()
+
+	go challenges.Run(ctx)
+
+	httpServer := &http.Server{
+		Addr:    serveAddr,
+		Handler: router,
+	}
+
+	go func() {
+		<-ctx.Done()
+		logger.Info("shutting down")
+		httpServer.Shutdown(context.Background())
+	}()
+
+	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func initApp(staleThreshold int, checker healthcheck.HealthChecker, signer *auth.Signer, store srvrepo.Store, geoResolver geoip.Resolver, challenges *challenge.Listener, recorder *metrics.Recorder, trustedProxies []netip.Prefix, logger logging.Logger) (http.Handler, *srvrepo.ServerRepository) {
+	router := gin.New()
 	router.Use(gin.Recovery())
+	router.Use(gzip.Gzip(gzip.DefaultCompression))
+	router.Use(httpapi.RequestID())
+	router.Use(httpapi.Tracing())
+	router.Use(httpapi.RequestLogger(logger))
+	router.Use(metrics.Instrument(recorder))
 
-	repository := srvrepo.NewServerRepository()
-	srvController := httpapi.NewServerController(repository)
+	threshold := time.Duration(staleThreshold) * time.Second
+	repository := srvrepo.NewServerRepository(store, threshold, srvrepo.DefaultPruneInterval, logger, recorder)
+	srvController := httpapi.NewServerController(repository, checker, geoResolver, challenges, trustedProxies, logger)
 
 	// Register endpoint handlers
-	router.GET("/reflection/ip", httpapi.HandleGetIP)
+	router.GET("/metrics", httpapi.HandleMetrics())
+	router.GET("/healthz", httpapi.HandleLiveness)
+	router.GET("/readyz", httpapi.HandleReadiness(repository))
+	router.GET("/reflection/ip", httpapi.HandleGetIP(logger, trustedProxies))
 	router.GET("/servers", srvController.HandleList)
-	router.POST("/servers/:server_id", srvController.HandleRegister)
-	router.PUT("/servers/:server_id", srvController.HandleUpdate)
-	router.DELETE("/servers/:server_id", srvController.HandleRemove)
-
-	// thread w/locking for the pruning operations
-	go pruneServers(repository, time.Duration(staleThreshold)*time.Second)
+	router.GET("/servers/stream", srvController.HandleStream)
+	router.GET("/servers/ws", srvController.HandleWS)
+	router.POST("/servers/:server_id/challenge", auth.RequireToken(signer, "register"), srvController.HandleChallenge)
+	router.POST("/servers/:server_id", auth.RequireToken(signer, "register"), srvController.HandleRegister)
+	router.PUT("/servers/:server_id", auth.RequireToken(signer, "register"), srvController.HandleUpdate)
+	router.DELETE("/servers/:server_id", auth.RequireToken(signer, "register"), srvController.HandleRemove)
+
+	router.POST("/admin/prune", auth.RequireToken(signer, "admin"), func(c *gin.Context) {
+		repository.Prune(threshold)
+		c.JSON(http.StatusOK, gin.H{"result": "pruned"})
+	})
 
-	return router
+	return router, repository
 }
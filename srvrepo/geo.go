@@ -0,0 +1,88 @@
+package srvrepo
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// GeoLocation holds the coarse geographic metadata resolved for a Server's IP
+// address by a geoip.Resolver. It's a pointer field on Server and is omitted
+// from JSON entirely (via omitempty) when GeoIP enrichment isn't configured.
+type GeoLocation struct {
+	Country   string  `json:"country,omitempty"`
+	Continent string  `json:"continent,omitempty"`
+	City      string  `json:"city,omitempty"`
+	Lat       float64 `json:"lat,omitempty"`
+	Lon       float64 `json:"lon,omitempty"`
+}
+
+// FilterByRegion returns the subset of servers whose GeoLocation matches the
+// given country and/or continent codes (case-insensitive). An empty country
+// or continent skips that half of the filter. Servers with no resolved
+// GeoLocation never match a non-empty filter.
+func FilterByRegion(servers []Server, country, continent string) []Server {
+	if country == "" && continent == "" {
+		return servers
+	}
+
+	filtered := make([]Server, 0, len(servers))
+	for _, srv := range servers {
+		if srv.Geo == nil {
+			continue
+		}
+		if country != "" && !strings.EqualFold(srv.Geo.Country, country) {
+			continue
+		}
+		if continent != "" && !strings.EqualFold(srv.Geo.Continent, continent) {
+			continue
+		}
+		filtered = append(filtered, srv)
+	}
+
+	return filtered
+}
+
+// SortByDistance sorts servers in place by great-circle distance from
+// (lat, lon), nearest first. Servers with no resolved GeoLocation sort last,
+// in their original relative order.
+func SortByDistance(servers []Server, lat, lon float64) {
+	sort.SliceStable(servers, func(i, j int) bool {
+		di, oki := distanceFrom(servers[i], lat, lon)
+		dj, okj := distanceFrom(servers[j], lat, lon)
+
+		if !oki {
+			return false
+		}
+		if !okj {
+			return true
+		}
+
+		return di < dj
+	})
+}
+
+func distanceFrom(srv Server, lat, lon float64) (float64, bool) {
+	if srv.Geo == nil {
+		return 0, false
+	}
+
+	return haversineKm(srv.Geo.Lat, srv.Geo.Lon, lat, lon), true
+}
+
+// haversineKm returns the great-circle distance in kilometers between two
+// lat/lon points.
+func haversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKm = 6371.0
+
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := rad(lat2 - lat1)
+	dLon := rad(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(rad(lat1))*math.Cos(rad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+
+	return earthRadiusKm * c
+}
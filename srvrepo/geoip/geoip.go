@@ -0,0 +1,78 @@
+// Package geoip resolves a server's IP address to coarse geographic
+// metadata. It mirrors srvrepo/healthcheck's pluggable Resolver/checker
+// design: a NoopResolver is the default so that deployments without a
+// GeoLite2 database pay no cost, and MaxMindResolver is swapped in when one
+// is configured.
+package geoip
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/srvrepo"
+	"github.com/oschwald/geoip2-golang"
+)
+
+// Resolver enriches an IP address with a srvrepo.GeoLocation. It returns a
+// nil GeoLocation (without an error) when the address can't be resolved to a
+// known location, e.g. private/reserved ranges.
+type Resolver interface {
+	Resolve(ip netip.Addr) (*srvrepo.GeoLocation, error)
+}
+
+// NoopResolver is a Resolver that never enriches anything. It's used when no
+// GeoIP database path is configured.
+type NoopResolver struct{}
+
+// Resolve satisfies the Resolver interface and always returns a nil
+// GeoLocation.
+func (NoopResolver) Resolve(ip netip.Addr) (*srvrepo.GeoLocation, error) {
+	return nil, nil
+}
+
+// MaxMindResolver is a Resolver backed by a MaxMind GeoLite2 City database.
+type MaxMindResolver struct {
+	db *geoip2.Reader
+}
+
+// NewMaxMindResolver opens the GeoLite2 City database at path and returns a
+// pointer to a new MaxMindResolver. Callers should Close it on shutdown.
+func NewMaxMindResolver(path string) (*MaxMindResolver, error) {
+	db, err := geoip2.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening GeoIP database %q: %w", path, err)
+	}
+
+	return &MaxMindResolver{db: db}, nil
+}
+
+// Close releases the underlying database file.
+func (r *MaxMindResolver) Close() error {
+	return r.db.Close()
+}
+
+// Resolve satisfies the Resolver interface, looking ip up in the GeoLite2
+// City database.
+func (r *MaxMindResolver) Resolve(ip netip.Addr) (*srvrepo.GeoLocation, error) {
+	record, err := r.db.City(net.IP(ip.AsSlice()))
+	if err != nil {
+		return nil, fmt.Errorf("looking up %s: %w", ip, err)
+	}
+
+	// An address with no record in the database (e.g. a private range)
+	// yields an empty record rather than an error.
+	if record.Country.IsoCode == "" && record.Continent.Code == "" {
+		return nil, nil
+	}
+
+	geo := &srvrepo.GeoLocation{
+		Country:   record.Country.IsoCode,
+		Continent: record.Continent.Code,
+		City:      record.City.Names["en"],
+		Lat:       record.Location.Latitude,
+		Lon:       record.Location.Longitude,
+	}
+
+	return geo, nil
+}
@@ -0,0 +1,17 @@
+// Package healthcheck defines pluggable liveness probes used to verify that a
+// registering server is actually reachable before it is admitted into the
+// repository.
+package healthcheck
+
+import (
+	"context"
+
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/srvrepo"
+)
+
+// HealthChecker defines the interface for probing a server's reachability.
+type HealthChecker interface {
+	// Probe attempts to verify that the given address is reachable, returning
+	// an error describing why the probe failed if it is not.
+	Probe(ctx context.Context, addr srvrepo.ServerAddress) error
+}
@@ -0,0 +1,66 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/srvrepo"
+)
+
+// HTTPChecker is a HealthChecker that verifies a server is reachable by
+// issuing a GET request against it and requiring a non-5xx response.
+type HTTPChecker struct {
+	// Path is appended to the server address to build the request URL, e.g.
+	// "/healthz". Defaults to "/" if empty.
+	Path string
+
+	// Timeout bounds how long we wait for the response.
+	Timeout time.Duration
+
+	client *http.Client
+}
+
+// NewHTTPChecker returns a pointer to a new HTTPChecker configured with the
+// given path and timeout.
+func NewHTTPChecker(path string, timeout time.Duration) *HTTPChecker {
+	return &HTTPChecker{
+		Path:    path,
+		Timeout: timeout,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Probe satisfies the HealthChecker interface, issuing a GET request against
+// the given address and requiring a non-5xx response.
+func (c *HTTPChecker) Probe(ctx context.Context, addr srvrepo.ServerAddress) error {
+	path := c.Path
+	if path == "" {
+		path = "/"
+	}
+
+	url := fmt.Sprintf("http://%s%s", addr.String(), path)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	client := c.client
+	if client == nil {
+		client = &http.Client{Timeout: c.Timeout}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("received status %d from %s", resp.StatusCode, url)
+	}
+
+	return nil
+}
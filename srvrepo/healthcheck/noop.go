@@ -0,0 +1,17 @@
+package healthcheck
+
+import (
+	"context"
+
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/srvrepo"
+)
+
+// NoopChecker is a HealthChecker that always reports success. It exists so
+// that tests (and deployments that don't want active probing) can opt out
+// without special-casing the registration path.
+type NoopChecker struct{}
+
+// Probe satisfies the HealthChecker interface and always returns nil.
+func (NoopChecker) Probe(ctx context.Context, addr srvrepo.ServerAddress) error {
+	return nil
+}
@@ -0,0 +1,35 @@
+package healthcheck
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/srvrepo"
+)
+
+// TCPChecker is a HealthChecker that verifies a server is reachable by
+// opening (and immediately closing) a TCP connection to it.
+type TCPChecker struct {
+	// Timeout bounds how long we wait for the connection to succeed.
+	Timeout time.Duration
+}
+
+// NewTCPChecker returns a pointer to a new TCPChecker configured with the
+// given timeout.
+func NewTCPChecker(timeout time.Duration) *TCPChecker {
+	return &TCPChecker{Timeout: timeout}
+}
+
+// Probe satisfies the HealthChecker interface, dialing the given address over
+// TCP and closing the connection on success.
+func (c *TCPChecker) Probe(ctx context.Context, addr srvrepo.ServerAddress) error {
+	d := net.Dialer{Timeout: c.Timeout}
+
+	conn, err := d.DialContext(ctx, "tcp", addr.String())
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
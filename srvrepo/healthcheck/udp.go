@@ -0,0 +1,97 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/logging"
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/srvrepo"
+)
+
+// pingPayload and pongPayload are the bytes exchanged during a UDP probe.
+var (
+	pingPayload = []byte("ping")
+	pongPayload = []byte("pong")
+)
+
+// UDPChecker is a HealthChecker that verifies a server is reachable by
+// sending a "ping" datagram and expecting a "pong" reply within Timeout,
+// retrying up to Retries times before giving up.
+type UDPChecker struct {
+	// Timeout bounds how long we wait for a single "pong" reply.
+	Timeout time.Duration
+
+	// Retries is the number of additional attempts made after the first
+	// attempt fails.
+	Retries int
+
+	logger logging.Logger
+}
+
+// NewUDPChecker returns a pointer to a new UDPChecker configured with the
+// given timeout and retry count.
+func NewUDPChecker(timeout time.Duration, retries int, logger logging.Logger) *UDPChecker {
+	return &UDPChecker{
+		Timeout: timeout,
+		Retries: retries,
+		logger:  logger,
+	}
+}
+
+// Probe satisfies the HealthChecker interface, sending "ping" over UDP to the
+// given address and waiting for a "pong" reply.
+func (c *UDPChecker) Probe(ctx context.Context, addr srvrepo.ServerAddress) error {
+	logger := logging.WithRequestID(c.logger, logging.RequestIDFromContext(ctx))
+
+	var lastErr error
+
+	for attempt := 0; attempt <= c.Retries; attempt++ {
+		logger.Debug("pinging registering server",
+			logging.String("event", "udp_probe"),
+			logging.String("addr", addr.String()),
+			logging.String("attempt", strconv.Itoa(attempt+1)),
+		)
+
+		if lastErr = c.probeOnce(ctx, addr); lastErr == nil {
+			return nil
+		}
+	}
+
+	logger.Error("udp health check failed", logging.Err(lastErr), logging.String("addr", addr.String()))
+
+	return fmt.Errorf("udp health check failed after %d attempt(s): %w", c.Retries+1, lastErr)
+}
+
+func (c *UDPChecker) probeOnce(ctx context.Context, addr srvrepo.ServerAddress) error {
+	conn, err := net.Dial("udp", addr.String())
+	if err != nil {
+		return fmt.Errorf("dialing %s: %w", addr.String(), err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(c.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	if _, err := conn.Write(pingPayload); err != nil {
+		return fmt.Errorf("writing ping: %w", err)
+	}
+
+	buf := make([]byte, len(pongPayload))
+	n, err := conn.Read(buf)
+	if err != nil {
+		return fmt.Errorf("reading pong: %w", err)
+	}
+
+	if !bytes.Equal(buf[:n], pongPayload) {
+		return fmt.Errorf("unexpected reply %q", buf[:n])
+	}
+
+	return nil
+}
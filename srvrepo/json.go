@@ -22,3 +22,10 @@ func (t jsonTime) MarshalJSON() ([]byte, error) {
 
 	return json.Marshal(&formatted)
 }
+
+// NewVerifiedAt wraps t for assignment to Server.VerifiedAt. jsonTime itself
+// is unexported, so callers outside this package can't construct one
+// directly; this is the constructor Server's other *jsonTime consumers use.
+func NewVerifiedAt(t time.Time) jsonTime {
+	return jsonTime{t}
+}
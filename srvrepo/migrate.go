@@ -0,0 +1,22 @@
+package srvrepo
+
+import "fmt"
+
+// Migrate copies every server from src into dst, for moving registrations
+// onto a new storage backend (e.g. memory -> bolt, or bolt -> redis) without
+// losing them. It returns the number of servers copied. Servers already
+// present in dst under the same ServerID are overwritten.
+func Migrate(dst, src Store) (int, error) {
+	servers, err := src.List()
+	if err != nil {
+		return 0, fmt.Errorf("listing source store: %w", err)
+	}
+
+	for i, srv := range servers {
+		if _, err := dst.Put(srv.ID(), srv); err != nil {
+			return i, fmt.Errorf("writing server %q to destination store: %w", srv.ID(), err)
+		}
+	}
+
+	return len(servers), nil
+}
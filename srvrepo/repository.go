@@ -0,0 +1,241 @@
+package srvrepo
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/logging"
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/metrics"
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/pubsub"
+)
+
+// DefaultPruneInterval is used by NewServerRepository when no interval is
+// given explicitly.
+const DefaultPruneInterval = 15 * time.Second
+
+// EventType identifies what happened to a server in an Event.
+type EventType string
+
+// Event types published by ServerRepository.
+const (
+	EventAdded   EventType = "added"
+	EventUpdated EventType = "updated"
+	EventRemoved EventType = "removed"
+	EventPruned  EventType = "pruned"
+)
+
+// Event describes a change to a single server, published by Register,
+// Remove, and Prune to anything listening via Subscribe.
+type Event struct {
+	Type   EventType `json:"type"`
+	Server Server    `json:"server"`
+}
+
+// ServerRepository is the public entry point for interacting with registered
+// servers. It delegates all storage to a pluggable Store (MemoryStore by
+// default) and layers on a background pruner that periodically expires
+// servers that have gone stale.
+type ServerRepository struct {
+	store Store
+
+	staleThreshold time.Duration
+	pruneInterval  time.Duration
+
+	broker *pubsub.Broker[Event]
+
+	logger   logging.Logger
+	recorder *metrics.Recorder
+
+	// stopCtx, cancel, and done are all set once here in
+	// NewServerRepository (never mutated afterward), so Run and Close can
+	// safely read them from different goroutines regardless of which one
+	// runs first. done is closed through doneOnce by whichever happens
+	// first: Run's loop returning, or cancel being invoked -- so Close
+	// never blocks forever waiting on a pruner that was never started.
+	stopCtx  context.Context
+	cancel   context.CancelFunc
+	done     chan struct{}
+	doneOnce *sync.Once
+}
+
+// NewServerRepository returns a pointer to a new initialized ServerRepository
+// backed by the given Store. staleThreshold is how long a server may go
+// without being re-registered before Run prunes it; pruneInterval is how
+// often the background pruner sweeps the store for stale entries. recorder
+// is updated on every Register, Remove, and Prune, so every configured Store
+// backend reports through the same metrics.
+func NewServerRepository(store Store, staleThreshold, pruneInterval time.Duration, logger logging.Logger, recorder *metrics.Recorder) *ServerRepository {
+	if pruneInterval <= 0 {
+		pruneInterval = DefaultPruneInterval
+	}
+
+	stopCtx, cancelStopCtx := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	doneOnce := &sync.Once{}
+
+	return &ServerRepository{
+		store:          store,
+		staleThreshold: staleThreshold,
+		pruneInterval:  pruneInterval,
+		broker:         &pubsub.Broker[Event]{},
+		logger:         logger,
+		recorder:       recorder,
+		stopCtx:        stopCtx,
+		cancel: func() {
+			cancelStopCtx()
+			doneOnce.Do(func() { close(done) })
+		},
+		done:     done,
+		doneOnce: doneOnce,
+	}
+}
+
+// Subscribe registers for live updates as servers are added, updated,
+// removed, or pruned. The returned channel is closed automatically once ctx
+// is done. Events are dropped for a subscriber that isn't keeping up, rather
+// than blocking the repository.
+func (r *ServerRepository) Subscribe(ctx context.Context) <-chan Event {
+	return r.broker.Subscribe(ctx)
+}
+
+// Ping checks whether the repository's backing Store is reachable, for use
+// by a readiness probe.
+func (r *ServerRepository) Ping(ctx context.Context) error {
+	return r.store.Ping(ctx)
+}
+
+// Has checks if a given ServerID already exists in the repository.
+func (r *ServerRepository) Has(id ServerID) bool {
+	_, exists, _ := r.store.Get(id)
+
+	return exists
+}
+
+// List returns a slice representation of the servers in the repository.
+func (r *ServerRepository) List() []Server {
+	serverList, err := r.store.List()
+	if err != nil {
+		return nil
+	}
+
+	return serverList
+}
+
+// Len returns the total number of servers currently registered.
+func (r *ServerRepository) Len() int {
+	return len(r.List())
+}
+
+// Register takes a Server and registers it with the repository, returning a
+// bool that represents whether the server already existed or not (true for
+// already exists, false otherwise), and a potential error if the registration
+// failed.
+func (r *ServerRepository) Register(srv Server) (bool, error) {
+	// TODO: Normalize? Validate?
+	existed, err := r.store.Put(srv.ID(), srv)
+	if err != nil {
+		return existed, err
+	}
+
+	eventType := EventAdded
+	if existed {
+		eventType = EventUpdated
+	}
+	r.broker.Publish(Event{Type: eventType, Server: srv})
+
+	r.recorder.RecordRegister()
+	r.updateGauges()
+
+	return existed, nil
+}
+
+// Remove takes a ServerID and removes the corresponding server from the
+// repository, returning a bool that represents whether the server existed or
+// not (true for exists, false otherwise).
+func (r *ServerRepository) Remove(id ServerID) bool {
+	srv, _, _ := r.store.Get(id)
+
+	exists, _ := r.store.Delete(id)
+	if exists {
+		r.broker.Publish(Event{Type: EventRemoved, Server: srv})
+
+		r.recorder.RecordRemove()
+		r.updateGauges()
+	}
+
+	return exists
+}
+
+// Prune takes a time.Duration representing the threshold of when a server's
+// last-seen "age" should be considered too old, removes those servers from
+// the repository, and publishes a Pruned event for each one removed.
+func (r *ServerRepository) Prune(threshold time.Duration) {
+	pruned, err := r.store.Expire(threshold)
+	if err != nil {
+		return
+	}
+
+	for _, srv := range pruned {
+		r.broker.Publish(Event{Type: EventPruned, Server: srv})
+	}
+
+	if len(pruned) > 0 {
+		r.recorder.RecordPrune(len(pruned))
+		r.updateGauges()
+	}
+}
+
+// updateGauges recomputes servers_current and servers_by_game_version from
+// the current contents of the store. It's called after every mutation
+// rather than kept incrementally, so it stays correct regardless of which
+// Store backend is configured (e.g. RedisStore entries that expire on their
+// own TTL rather than through Prune).
+func (r *ServerRepository) updateGauges() {
+	serverList := r.List()
+
+	r.recorder.SetServersCurrent(len(serverList))
+
+	byVersion := make(map[int]int)
+	for _, srv := range serverList {
+		byVersion[srv.GameVersion]++
+	}
+	r.recorder.SetServersByGameVersion(byVersion)
+}
+
+// Run starts the background pruner, sweeping the repository for stale
+// servers every pruneInterval, until ctx is cancelled or Close is called.
+// Run blocks the calling goroutine, so callers typically invoke it with
+// `go repository.Run(ctx)`.
+func (r *ServerRepository) Run(ctx context.Context) {
+	defer r.doneOnce.Do(func() { close(r.done) })
+
+	ticker := time.NewTicker(r.pruneInterval)
+	defer ticker.Stop()
+
+	r.logger.Info("server repository pruner started", logging.String("prune_interval", r.pruneInterval.String()))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.stopCtx.Done():
+			return
+		case <-ticker.C:
+			before := r.Len()
+			r.Prune(r.staleThreshold)
+			if pruned := before - r.Len(); pruned > 0 {
+				r.logger.Info("pruned stale servers", logging.String("count", strconv.Itoa(pruned)))
+			}
+		}
+	}
+}
+
+// Close stops the background pruner started by Run. Close is safe to call
+// even if Run was never started: done is closed by cancel itself in that
+// case, so the wait below returns immediately instead of blocking forever.
+func (r *ServerRepository) Close() {
+	r.cancel()
+	<-r.done
+}
@@ -4,11 +4,10 @@ package srvrepo
 
 import (
 	"fmt"
-	"github.com/golang/glog"
 	"net"
+	"net/netip"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 )
 
@@ -18,9 +17,19 @@ type Server struct {
 
 	Name        string `json:"name"`
 	GameVersion int    `json:"game_version"`
-	IsJoinable bool		`json:"is_joinable"`
+	IsJoinable  bool   `json:"is_joinable"`
 
 	LastSeen jsonTime `json:"last_seen"`
+
+	// Geo is the GeoLocation resolved for IP by a geoip.Resolver, if GeoIP
+	// enrichment is configured. It's nil (and omitted from JSON) otherwise.
+	Geo *GeoLocation `json:"geo,omitempty"`
+
+	// VerifiedAt is when this server last echoed back a challenge nonce over
+	// UDP, proving it's actually reachable at its claimed address. It's nil
+	// (and omitted from JSON) until the challenge-response handshake
+	// succeeds.
+	VerifiedAt *jsonTime `json:"verified_at,omitempty"`
 }
 
 // ID returns the ServerID for a server, generated based on its internal data.
@@ -50,14 +59,21 @@ func (s *Server) Validate() error {
 	return nil
 }
 
-// ServerAddress defines the structure of a server address.
+// ServerAddress defines the structure of a server address. IP is a
+// netip.Addr rather than a net.IP so that a Server can carry either an IPv4
+// or an IPv6 address, and so that two equivalent addresses compare equal
+// with ==.
 type ServerAddress struct {
-	IP   net.IP `json:"ip"`
-	Port int    `json:"port"`
+	IP   netip.Addr `json:"ip"`
+	Port int        `json:"port"`
 }
 
-// ParseServerAddress parses a string address into a ServerAddress, returning
-// the parsed value and any errors that occurred during parsing.
+// ParseServerAddress parses a "host:port" (or "[host]:port" for IPv6) string
+// address into a ServerAddress, returning the parsed value and any errors
+// that occurred during parsing. The parsed IP is canonicalized (IPv4-mapped
+// IPv6 addresses are unwrapped to plain IPv4, and any IPv6 zone is stripped)
+// so that two textually different addresses for the same host always parse
+// to the same ServerAddress, and therefore the same ServerID.
 func ParseServerAddress(s string) (ServerAddress, error) {
 	var addr ServerAddress
 
@@ -66,14 +82,18 @@ func ParseServerAddress(s string) (ServerAddress, error) {
 		return addr, err
 	}
 
-	ip := net.ParseIP(rawIP)
+	ip, err := netip.ParseAddr(rawIP)
+	if err != nil {
+		return addr, fmt.Errorf("invalid IP address: %w", err)
+	}
+
 	port, err := strconv.Atoi(rawPort)
 	if err != nil {
 		return addr, fmt.Errorf("invalid port number with err: %w", err)
 	}
 
 	addr = ServerAddress{
-		IP:   ip,
+		IP:   ip.Unmap().WithZone(""),
 		Port: port,
 	}
 
@@ -81,19 +101,23 @@ func ParseServerAddress(s string) (ServerAddress, error) {
 }
 
 // String satisfies the fmt.Stringer interface and returns a string form of the
-// ServerAddress structure.
+// ServerAddress structure, bracketing the host when it's an IPv6 address
+// (e.g. "[::1]:1234").
 func (a *ServerAddress) String() string {
-	return net.JoinHostPort(
-		a.IP.String(),
-		strconv.Itoa(a.Port),
-	)
+	return a.AddrPort().String()
+}
+
+// AddrPort returns the ServerAddress as a netip.AddrPort, combining its IP
+// and Port.
+func (a *ServerAddress) AddrPort() netip.AddrPort {
+	return netip.AddrPortFrom(a.IP, uint16(a.Port))
 }
 
 // Validate runs validations on the value and returns an error if the value is
 // invalid for any reason.
 func (a *ServerAddress) Validate() error {
-	if a.IP.To4() == nil {
-		return fmt.Errorf("IP is not a valid IPv4 address")
+	if !a.IP.IsValid() {
+		return fmt.Errorf("IP is not a valid IP address")
 	}
 
 	if a.Port < portRangeMin || a.Port > portRangeMax {
@@ -105,96 +129,3 @@ func (a *ServerAddress) Validate() error {
 
 // ServerID defines the identifier of a particular server.
 type ServerID string
-
-// ServerRepository defines the structure for an in-memory server repository.
-type ServerRepository struct {
-	servers map[ServerID]Server
-
-	mu sync.RWMutex
-}
-
-// NewServerRepository returns a pointer to a new initialized ServerRepository.
-func NewServerRepository() *ServerRepository {
-	return &ServerRepository{
-		servers: make(map[ServerID]Server),
-	}
-}
-
-// Check if an given ServerID already exists in the repository
-func (r *ServerRepository) Has(id ServerID) bool {
-	alreadyExists := false
-
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	_, alreadyExists = r.servers[id]
-
-	return alreadyExists
-}
-
-// List returns a slice representation of the servers in the repository.
-func (r *ServerRepository) List() []Server {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	serverList := make([]Server, 0, len(r.servers))
-
-	for _, srv := range r.servers {
-		serverList = append(serverList, srv)
-	}
-
-	return serverList
-}
-
-// Register takes a Server and registers it with the repository, returning a
-// bool that represents whether the server already existed or not (true for
-// already exists, false otherwise), and a potential error if the registration
-// failed.
-func (r *ServerRepository) Register(srv Server) (bool, error) {
-	alreadyExists := false
-	var err error
-
-	// TODO: Normalize? Validate?
-	id := srv.ID()
-
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	_, alreadyExists = r.servers[id]
-	r.servers[id] = srv
-
-	return alreadyExists, err
-}
-
-// Remove takes a ServerID and removes the corresponding server from the
-// repository, returning a bool that represents whether the server existed or
-// not (true for exists, false otherwise).
-func (r *ServerRepository) Remove(id ServerID) bool {
-	exists := false
-
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	_, exists = r.servers[id]
-	delete(r.servers, id)
-
-	return exists
-}
-
-// Prune takes a time.Duration representing the threshold of when a server's
-// last-seen "age" should be considered too old, and removes those servers from
-// the repository.
-func (r *ServerRepository) Prune(threshold time.Duration) {
-	cutoff := time.Now().Add(-threshold)
-
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	for id, srv := range r.servers {
-		if srv.LastSeen.Before(cutoff) {
-			glog.Infof("Pruning server: %s\n", id)
-
-			delete(r.servers, id)
-		}
-	}
-}
@@ -0,0 +1,64 @@
+package srvrepo
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/logging"
+)
+
+// Store defines a pluggable storage backend for registered servers. The
+// in-memory sharded map (MemoryStore) is the default; RedisStore and
+// BoltStore exist so the repository can survive restarts and run behind a
+// load balancer with more than one instance.
+type Store interface {
+	// Put inserts or replaces the server under its ServerID, returning
+	// whether an entry already existed for that ID.
+	Put(id ServerID, srv Server) (bool, error)
+
+	// Get returns the server registered under the given ServerID, if any.
+	Get(id ServerID) (Server, bool, error)
+
+	// Delete removes the server registered under the given ServerID,
+	// returning whether it existed.
+	Delete(id ServerID) (bool, error)
+
+	// List returns every server currently in the store.
+	List() ([]Server, error)
+
+	// Expire removes every server whose LastSeen is older than threshold,
+	// returning the servers that were removed.
+	Expire(threshold time.Duration) ([]Server, error)
+
+	// Ping reports whether the backend is reachable, for use by a readiness
+	// probe.
+	Ping(ctx context.Context) error
+}
+
+// OpenStore builds the Store described by spec, which is one of "memory",
+// "redis://host:port", or "bolt:///path/to/db". ttl is only meaningful to
+// backends (such as RedisStore) that expire entries natively rather than
+// relying on a caller-driven Expire sweep. It's shared by the repository
+// server and fugitivectl so both agree on the same set of storage backends
+// and spec syntax.
+func OpenStore(spec string, ttl time.Duration, logger logging.Logger) (Store, error) {
+	if spec == "memory" {
+		return NewMemoryStore(logger), nil
+	}
+
+	u, err := url.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("parsing store spec %q: %w", spec, err)
+	}
+
+	switch u.Scheme {
+	case "redis":
+		return NewRedisStore(u.Host, ttl), nil
+	case "bolt":
+		return NewBoltStore(u.Path)
+	default:
+		return nil, fmt.Errorf("unknown store scheme %q (want memory, redis://, or bolt://)", u.Scheme)
+	}
+}
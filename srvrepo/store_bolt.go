@@ -0,0 +1,160 @@
+package srvrepo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltBucket is the single bucket every server is stored under.
+var boltBucket = []byte("servers")
+
+// BoltStore is a Store backed by a local BoltDB file, giving single-node
+// persistence of registered servers across repository restarts.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (creating if necessary) a BoltDB database at path and
+// returns a pointer to a BoltStore backed by it.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("opening bolt db: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating bucket: %w", err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// Close closes the underlying BoltDB file.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// Put satisfies the Store interface.
+func (s *BoltStore) Put(id ServerID, srv Server) (bool, error) {
+	data, err := json.Marshal(srv)
+	if err != nil {
+		return false, fmt.Errorf("marshalling server: %w", err)
+	}
+
+	var alreadyExists bool
+
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltBucket)
+		alreadyExists = b.Get([]byte(id)) != nil
+
+		return b.Put([]byte(id), data)
+	})
+
+	return alreadyExists, err
+}
+
+// Get satisfies the Store interface.
+func (s *BoltStore) Get(id ServerID) (Server, bool, error) {
+	var srv Server
+	var exists bool
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(boltBucket).Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+
+		exists = true
+		return json.Unmarshal(data, &srv)
+	})
+
+	return srv, exists, err
+}
+
+// Delete satisfies the Store interface.
+func (s *BoltStore) Delete(id ServerID) (bool, error) {
+	var existed bool
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltBucket)
+		existed = b.Get([]byte(id)) != nil
+
+		return b.Delete([]byte(id))
+	})
+
+	return existed, err
+}
+
+// List satisfies the Store interface.
+func (s *BoltStore) List() ([]Server, error) {
+	serverList := make([]Server, 0)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).ForEach(func(k, v []byte) error {
+			var srv Server
+			if err := json.Unmarshal(v, &srv); err != nil {
+				return err
+			}
+
+			serverList = append(serverList, srv)
+			return nil
+		})
+	})
+
+	return serverList, err
+}
+
+// Expire satisfies the Store interface.
+func (s *BoltStore) Expire(threshold time.Duration) ([]Server, error) {
+	cutoff := time.Now().Add(-threshold)
+
+	var pruned []Server
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltBucket)
+
+		var staleIDs [][]byte
+		err := b.ForEach(func(k, v []byte) error {
+			var srv Server
+			if err := json.Unmarshal(v, &srv); err != nil {
+				return err
+			}
+
+			if srv.LastSeen.Before(cutoff) {
+				staleIDs = append(staleIDs, append([]byte(nil), k...))
+				pruned = append(pruned, srv)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, id := range staleIDs {
+			if err := b.Delete(id); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return pruned, err
+}
+
+// Ping satisfies the Store interface, reporting whether the underlying
+// BoltDB file is still open and usable.
+func (s *BoltStore) Ping(ctx context.Context) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		return nil
+	})
+}
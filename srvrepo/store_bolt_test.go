@@ -0,0 +1,18 @@
+package srvrepo
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBoltStoreCompliance(t *testing.T) {
+	storeCompliance(t, func(t *testing.T) Store {
+		store, err := NewBoltStore(filepath.Join(t.TempDir(), "test.db"))
+		if err != nil {
+			t.Fatalf("NewBoltStore: %v", err)
+		}
+		t.Cleanup(func() { store.Close() })
+
+		return store
+	}, true)
+}
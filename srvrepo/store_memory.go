@@ -0,0 +1,137 @@
+package srvrepo
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/logging"
+)
+
+// numShards is the number of independent shards MemoryStore splits its
+// servers across. Each shard guards its own map with its own RWMutex, so
+// readers/writers of different shards never contend with each other. A
+// power of two keeps the modulo-by-hash distribution cheap and even.
+const numShards = 32
+
+// shard is a single partition of the store's server map, along with the lock
+// that guards it.
+type shard struct {
+	mu      sync.RWMutex
+	servers map[ServerID]Server
+}
+
+// MemoryStore is a concurrent-safe, in-memory Store. Servers are distributed
+// across a fixed number of shards so that registrations, removals, and
+// lookups against different servers don't block each other, and List never
+// holds a single store-wide lock.
+type MemoryStore struct {
+	shards [numShards]*shard
+
+	logger logging.Logger
+}
+
+// NewMemoryStore returns a pointer to a new initialized MemoryStore.
+func NewMemoryStore(logger logging.Logger) *MemoryStore {
+	m := &MemoryStore{logger: logger}
+
+	for i := range m.shards {
+		m.shards[i] = &shard{
+			servers: make(map[ServerID]Server),
+		}
+	}
+
+	return m
+}
+
+// shardFor returns the shard responsible for the given ServerID.
+func (m *MemoryStore) shardFor(id ServerID) *shard {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+
+	return m.shards[h.Sum32()%numShards]
+}
+
+// Put satisfies the Store interface.
+func (m *MemoryStore) Put(id ServerID, srv Server) (bool, error) {
+	s := m.shardFor(id)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, alreadyExists := s.servers[id]
+	s.servers[id] = srv
+
+	return alreadyExists, nil
+}
+
+// Get satisfies the Store interface.
+func (m *MemoryStore) Get(id ServerID) (Server, bool, error) {
+	s := m.shardFor(id)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	srv, exists := s.servers[id]
+
+	return srv, exists, nil
+}
+
+// Delete satisfies the Store interface.
+func (m *MemoryStore) Delete(id ServerID) (bool, error) {
+	s := m.shardFor(id)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, exists := s.servers[id]
+	delete(s.servers, id)
+
+	return exists, nil
+}
+
+// List satisfies the Store interface. Each shard is locked only long enough
+// to copy its contents, so List never blocks writers across the whole store
+// at once.
+func (m *MemoryStore) List() ([]Server, error) {
+	serverList := make([]Server, 0)
+
+	for _, s := range m.shards {
+		s.mu.RLock()
+		for _, srv := range s.servers {
+			serverList = append(serverList, srv)
+		}
+		s.mu.RUnlock()
+	}
+
+	return serverList, nil
+}
+
+// Expire satisfies the Store interface.
+func (m *MemoryStore) Expire(threshold time.Duration) ([]Server, error) {
+	cutoff := time.Now().Add(-threshold)
+
+	var pruned []Server
+
+	for _, s := range m.shards {
+		s.mu.Lock()
+		for id, srv := range s.servers {
+			if srv.LastSeen.Before(cutoff) {
+				m.logger.Info("pruning server", logging.String("server_id", string(id)))
+
+				pruned = append(pruned, srv)
+				delete(s.servers, id)
+			}
+		}
+		s.mu.Unlock()
+	}
+
+	return pruned, nil
+}
+
+// Ping satisfies the Store interface. MemoryStore has no external backend to
+// reach, so it's always ready.
+func (m *MemoryStore) Ping(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,18 @@
+package srvrepo
+
+import (
+	"testing"
+
+	"github.com/FugitiveTheGame/Fugitive3dServerRepository/internal/logging"
+)
+
+func TestMemoryStoreCompliance(t *testing.T) {
+	logger, err := logging.NewDevelopment()
+	if err != nil {
+		t.Fatalf("logging.NewDevelopment: %v", err)
+	}
+
+	storeCompliance(t, func(t *testing.T) Store {
+		return NewMemoryStore(logger)
+	}, true)
+}
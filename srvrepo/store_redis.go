@@ -0,0 +1,122 @@
+package srvrepo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKeyPrefix namespaces every key this store writes, so the repository
+// can share a Redis instance with other data.
+const redisKeyPrefix = "fugitive:server:"
+
+// RedisStore is a Store backed by Redis. Entries are written with a TTL
+// matching the repository's stale threshold, so Redis itself expires stale
+// servers and Expire becomes a no-op.
+type RedisStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisStore returns a pointer to a new RedisStore connected to addr
+// (host:port), with entries set to expire after ttl.
+func NewRedisStore(addr string, ttl time.Duration) *RedisStore {
+	return &RedisStore{
+		client: redis.NewClient(&redis.Options{Addr: addr}),
+		ttl:    ttl,
+	}
+}
+
+func (s *RedisStore) key(id ServerID) string {
+	return redisKeyPrefix + string(id)
+}
+
+// Put satisfies the Store interface.
+func (s *RedisStore) Put(id ServerID, srv Server) (bool, error) {
+	ctx := context.Background()
+
+	existed, err := s.client.Exists(ctx, s.key(id)).Result()
+	if err != nil {
+		return false, fmt.Errorf("checking existing entry: %w", err)
+	}
+
+	data, err := json.Marshal(srv)
+	if err != nil {
+		return false, fmt.Errorf("marshalling server: %w", err)
+	}
+
+	if err := s.client.Set(ctx, s.key(id), data, s.ttl).Err(); err != nil {
+		return false, fmt.Errorf("writing entry: %w", err)
+	}
+
+	return existed > 0, nil
+}
+
+// Get satisfies the Store interface.
+func (s *RedisStore) Get(id ServerID) (Server, bool, error) {
+	var srv Server
+
+	data, err := s.client.Get(context.Background(), s.key(id)).Bytes()
+	if err == redis.Nil {
+		return srv, false, nil
+	} else if err != nil {
+		return srv, false, fmt.Errorf("reading entry: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &srv); err != nil {
+		return srv, false, fmt.Errorf("unmarshalling server: %w", err)
+	}
+
+	return srv, true, nil
+}
+
+// Delete satisfies the Store interface.
+func (s *RedisStore) Delete(id ServerID) (bool, error) {
+	n, err := s.client.Del(context.Background(), s.key(id)).Result()
+	if err != nil {
+		return false, fmt.Errorf("deleting entry: %w", err)
+	}
+
+	return n > 0, nil
+}
+
+// List satisfies the Store interface, scanning for every key under
+// redisKeyPrefix rather than blocking Redis with a KEYS call.
+func (s *RedisStore) List() ([]Server, error) {
+	ctx := context.Background()
+
+	serverList := make([]Server, 0)
+	iter := s.client.Scan(ctx, 0, redisKeyPrefix+"*", 0).Iterator()
+
+	for iter.Next(ctx) {
+		data, err := s.client.Get(ctx, iter.Val()).Bytes()
+		if err == redis.Nil {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("reading entry: %w", err)
+		}
+
+		var srv Server
+		if err := json.Unmarshal(data, &srv); err != nil {
+			return nil, fmt.Errorf("unmarshalling server: %w", err)
+		}
+
+		serverList = append(serverList, srv)
+	}
+
+	return serverList, iter.Err()
+}
+
+// Expire satisfies the Store interface. This is a no-op for RedisStore since
+// entries are written with a TTL and expire on their own.
+func (s *RedisStore) Expire(threshold time.Duration) ([]Server, error) {
+	return nil, nil
+}
+
+// Ping satisfies the Store interface, reporting whether Redis is reachable.
+func (s *RedisStore) Ping(ctx context.Context) error {
+	return s.client.Ping(ctx).Err()
+}
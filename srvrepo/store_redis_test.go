@@ -0,0 +1,40 @@
+package srvrepo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+const redisTestAddr = "127.0.0.1:6379"
+
+func TestRedisStoreCompliance(t *testing.T) {
+	probe := NewRedisStore(redisTestAddr, time.Minute)
+	if err := probe.Ping(context.Background()); err != nil {
+		t.Skipf("no redis reachable at %s, skipping: %v", redisTestAddr, err)
+	}
+
+	// Expire is intentionally a no-op on RedisStore: entries are written
+	// with a TTL and expired by Redis itself, not by a Prune sweep.
+	storeCompliance(t, func(t *testing.T) Store {
+		store := NewRedisStore(redisTestAddr, time.Minute)
+		t.Cleanup(func() {
+			for _, srv := range mustList(t, store) {
+				store.Delete(srv.ID())
+			}
+		})
+
+		return store
+	}, false)
+}
+
+func mustList(t *testing.T, store Store) []Server {
+	t.Helper()
+
+	list, err := store.List()
+	if err != nil {
+		t.Fatalf("List (cleanup): %v", err)
+	}
+
+	return list
+}
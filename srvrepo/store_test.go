@@ -0,0 +1,150 @@
+package srvrepo
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+// testServer returns a distinct, valid Server for use in store tests, keyed
+// off i so callers can register several without colliding.
+func testServer(i int) Server {
+	return Server{
+		ServerAddress: ServerAddress{
+			IP:   netip.MustParseAddr("127.0.0.1"),
+			Port: 10000 + i,
+		},
+		Name:        fmt.Sprintf("test-server-%d", i),
+		GameVersion: 1,
+		IsJoinable:  true,
+		LastSeen:    jsonTime{time.Now()},
+	}
+}
+
+// storeCompliance exercises the behavior every Store implementation must
+// provide identically, regardless of backing technology. newStore must
+// return a fresh, empty Store for each subtest. supportsExpire should be
+// false for backends (such as RedisStore) that expire entries on their own
+// native TTL rather than through an explicit Expire call.
+func storeCompliance(t *testing.T, newStore func(t *testing.T) Store, supportsExpire bool) {
+	t.Run("PutGetDelete", func(t *testing.T) {
+		store := newStore(t)
+		srv := testServer(1)
+
+		existed, err := store.Put(srv.ID(), srv)
+		if err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+		if existed {
+			t.Fatalf("Put: got existed=true, want false for a new server")
+		}
+
+		got, ok, err := store.Get(srv.ID())
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if !ok {
+			t.Fatalf("Get: server was not found")
+		}
+		if got.Name != srv.Name {
+			t.Fatalf("Get: got name %q, want %q", got.Name, srv.Name)
+		}
+
+		existed, err = store.Put(srv.ID(), srv)
+		if err != nil {
+			t.Fatalf("Put (re-register): %v", err)
+		}
+		if !existed {
+			t.Fatalf("Put (re-register): got existed=false, want true")
+		}
+
+		deleted, err := store.Delete(srv.ID())
+		if err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if !deleted {
+			t.Fatalf("Delete: got false, want true")
+		}
+
+		if _, ok, err := store.Get(srv.ID()); err != nil {
+			t.Fatalf("Get (after delete): %v", err)
+		} else if ok {
+			t.Fatalf("Get (after delete): server still found")
+		}
+	})
+
+	t.Run("DeleteMissing", func(t *testing.T) {
+		store := newStore(t)
+
+		missing := testServer(1)
+		deleted, err := store.Delete(missing.ID())
+		if err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if deleted {
+			t.Fatalf("Delete: got true, want false for a server that was never registered")
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		store := newStore(t)
+
+		for i := 1; i <= 3; i++ {
+			srv := testServer(i)
+			if _, err := store.Put(srv.ID(), srv); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+		}
+
+		list, err := store.List()
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(list) != 3 {
+			t.Fatalf("List: got %d servers, want 3", len(list))
+		}
+	})
+
+	if supportsExpire {
+		t.Run("Expire", func(t *testing.T) {
+			store := newStore(t)
+
+			stale := testServer(1)
+			stale.LastSeen = jsonTime{time.Now().Add(-time.Hour)}
+			if _, err := store.Put(stale.ID(), stale); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+
+			fresh := testServer(2)
+			if _, err := store.Put(fresh.ID(), fresh); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+
+			pruned, err := store.Expire(time.Minute)
+			if err != nil {
+				t.Fatalf("Expire: %v", err)
+			}
+			if len(pruned) != 1 || pruned[0].ID() != stale.ID() {
+				t.Fatalf("Expire: got %v, want only %s pruned", pruned, stale.ID())
+			}
+
+			list, err := store.List()
+			if err != nil {
+				t.Fatalf("List (after expire): %v", err)
+			}
+			if len(list) != 1 || list[0].ID() != fresh.ID() {
+				t.Fatalf("List (after expire): got %v, want only %s", list, fresh.ID())
+			}
+		})
+	}
+
+	t.Run("Ping", func(t *testing.T) {
+		store := newStore(t)
+
+		if err := store.Ping(context.Background()); err != nil {
+			t.Fatalf("Ping: %v", err)
+		}
+	})
+}